@@ -0,0 +1,79 @@
+package zipfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+// buildDeflateZip returns a zip archive (as raw bytes) containing a single Deflate entry
+// of size bytes, filled with pseudo-random content. Random bytes do not compress, so the
+// entry is written as a run of stored deflate blocks, enough of them that a naive
+// byte-offset checkpoint would land mid-block.
+func buildDeflateZip(t *testing.T, name string, size int) ([]byte, []byte) {
+	t.Helper()
+
+	content := make([]byte, size)
+	rnd := rand.New(rand.NewSource(1))
+	rnd.Read(content)
+
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	hdr := &zip.FileHeader{Name: name, Method: zip.Deflate}
+	f, err := w.CreateHeader(hdr)
+	if err != nil {
+		t.Fatalf("creating %s: %v", name, err)
+	}
+	if _, err := f.Write(content); err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("closing test archive: %v", err)
+	}
+	return buf.Bytes(), content
+}
+
+// TestSeekableDeflateFileSeekAfterSequentialRead reproduces the scenario from the
+// chunk0-6 review: read a large Deflate entry sequentially (so it decodes past several
+// 256KiB-ish stretches of compressed data), then seek backward and forward on the same
+// handle and confirm the bytes served still match the original content.
+func TestSeekableDeflateFileSeekAfterSequentialRead(t *testing.T) {
+	const size = 700 * 1024
+	data, want := buildDeflateZip(t, "big.bin", size)
+
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("reading test archive: %v", err)
+	}
+	entry := z.File[0]
+	offset, err := entry.DataOffset()
+	if err != nil {
+		t.Fatalf("DataOffset: %v", err)
+	}
+
+	sdf, err := newSeekableDeflateFile(entry, bytes.NewReader(data), offset)
+	if err != nil {
+		t.Fatalf("newSeekableDeflateFile: %v", err)
+	}
+	defer sdf.Close()
+
+	if _, err := io.Copy(io.Discard, sdf); err != nil {
+		t.Fatalf("sequential read: %v", err)
+	}
+
+	for _, seekTo := range []int64{0, size / 2, size - 4096, 1024} {
+		if _, err := sdf.Seek(seekTo, io.SeekStart); err != nil {
+			t.Fatalf("Seek(%d): %v", seekTo, err)
+		}
+		got := make([]byte, 4096)
+		n, err := io.ReadFull(sdf, got)
+		if err != nil {
+			t.Fatalf("Read after Seek(%d): %v", seekTo, err)
+		}
+		if !bytes.Equal(got[:n], want[seekTo:seekTo+int64(n)]) {
+			t.Fatalf("content after Seek(%d) mismatches original", seekTo)
+		}
+	}
+}
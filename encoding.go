@@ -0,0 +1,123 @@
+package zipfs
+
+import (
+	"archive/zip"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// preEncodedVariants are tried, in order, against the request's Accept-Encoding header.
+// Brotli is tried before gzip since, at a comparable quality setting, it tends to produce
+// the smaller file; the first one both present in the archive and accepted by the client
+// wins.
+var preEncodedVariants = []struct {
+	suffix   string
+	encoding string
+}{
+	{".br", "br"},
+	{".gz", "gzip"},
+}
+
+// Handler wraps fs in an http.Handler that transparently serves a precompressed sibling
+// of the requested file -- e.g. foo.css.gz or foo.css.br, stored with zip.Store -- when
+// the client's Accept-Encoding allows it. http.FileSystem.Open has no access to request
+// headers, so this negotiation can only happen at the Handler level.
+//
+// It falls through to serving the plain file when fs isn't zip-backed, no encoded
+// sibling exists, the sibling isn't zero-copy servable, or the client doesn't accept any
+// of the available encodings.
+func Handler(fs http.FileSystem) http.Handler {
+	zfs, ok := fs.(*zipFS)
+	if !ok {
+		return http.FileServer(fs)
+	}
+	return &encodingHandler{fs: zfs, inner: http.FileServer(fs)}
+}
+
+type encodingHandler struct {
+	fs    *zipFS
+	inner http.Handler
+}
+
+func (h *encodingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Vary", "Accept-Encoding")
+
+	name := path.Clean("/" + r.URL.Path)
+	accepted := acceptedEncodings(r.Header.Get("Accept-Encoding"))
+
+	for _, variant := range preEncodedVariants {
+		if !accepted[variant.encoding] {
+			continue
+		}
+		if h.serveVariant(w, r, name, variant.suffix, variant.encoding) {
+			return
+		}
+	}
+
+	h.inner.ServeHTTP(w, r)
+}
+
+// serveVariant attempts to serve name+suffix in place of name, reporting whether it did.
+func (h *encodingHandler) serveVariant(w http.ResponseWriter, r *http.Request, name, suffix, encoding string) bool {
+	node, found := h.fs.trie.Find(name + suffix)
+	if !found {
+		return false
+	}
+	entry, ok := node.meta.(*zip.File)
+	if !ok || h.fs.readerAt == nil || entry.Method != zip.Store {
+		return false
+	}
+	offset, err := entry.DataOffset()
+	if err != nil {
+		return false
+	}
+
+	if ctype := mime.TypeByExtension(path.Ext(name)); ctype != "" {
+		w.Header().Set("Content-Type", ctype)
+	}
+	w.Header().Set("Content-Encoding", encoding)
+
+	content := io.NewSectionReader(h.fs.readerAt, offset, int64(entry.UncompressedSize64))
+	http.ServeContent(w, r, name, entry.Modified, content)
+	return true
+}
+
+// acceptedEncodings parses an Accept-Encoding header into the set of encodings the
+// client actually accepts, honoring "q=0" as an explicit refusal per RFC 7231 §5.3.1
+// (e.g. "gzip;q=0, br" accepts br only).
+func acceptedEncodings(header string) map[string]bool {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		enc := strings.TrimSpace(fields[0])
+		if enc == "" || enc == "identity" {
+			continue
+		}
+		if encodingQZero(fields[1:]) {
+			delete(accepted, enc)
+			continue
+		}
+		accepted[enc] = true
+	}
+	return accepted
+}
+
+// encodingQZero reports whether any of params (the ";"-separated fields following an
+// encoding token) is a "q" parameter equal to 0.
+func encodingQZero(params []string) bool {
+	for _, p := range params {
+		name, value, ok := strings.Cut(strings.TrimSpace(p), "=")
+		if !ok || strings.TrimSpace(name) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err == nil && q == 0 {
+			return true
+		}
+	}
+	return false
+}
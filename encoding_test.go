@@ -0,0 +1,136 @@
+package zipfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// buildEncodingZip returns a zip archive (as raw bytes) containing a plain file and
+// precompressed gzip/brotli siblings, all stored with zip.Store as Handler requires.
+func buildEncodingZip(t *testing.T) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+
+	writeFile(t, w, "foo.css", 0644, "plain")
+	writeFile(t, w, "foo.css.gz", 0644, "gzip-body")
+	writeFile(t, w, "foo.css.br", 0644, "brotli-body")
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("closing test archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func newEncodingHandler(t *testing.T) http.Handler {
+	t.Helper()
+	data := buildEncodingZip(t)
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("reading test archive: %v", err)
+	}
+	fs := NewZipFSWithReaderAt(z, bytes.NewReader(data))
+	return Handler(fs)
+}
+
+func getWithAcceptEncoding(t *testing.T, h http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/foo.css", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerPrefersBrotliOverGzip(t *testing.T) {
+	h := newEncodingHandler(t)
+	rec := getWithAcceptEncoding(t, h, "gzip, br")
+
+	if got := rec.Header().Get("Content-Encoding"); got != "br" {
+		t.Fatalf("Content-Encoding = %q, want %q", got, "br")
+	}
+	if rec.Body.String() != "brotli-body" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "brotli-body")
+	}
+}
+
+func TestHandlerFallsBackToGzipWhenBrotliUnavailable(t *testing.T) {
+	h := newEncodingHandler(t)
+	rec := getWithAcceptEncoding(t, h, "gzip")
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want %q", got, "gzip")
+	}
+	if rec.Body.String() != "gzip-body" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "gzip-body")
+	}
+}
+
+func TestHandlerHonorsQZeroRefusal(t *testing.T) {
+	h := newEncodingHandler(t)
+	rec := getWithAcceptEncoding(t, h, "br;q=0, gzip")
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want %q (br refused via q=0)", got, "gzip")
+	}
+	if rec.Body.String() != "gzip-body" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "gzip-body")
+	}
+}
+
+func TestHandlerFallsThroughToPlainFileWhenNoEncodingAccepted(t *testing.T) {
+	h := newEncodingHandler(t)
+	rec := getWithAcceptEncoding(t, h, "deflate")
+
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q, want unset", got)
+	}
+	if rec.Body.String() != "plain" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "plain")
+	}
+}
+
+func TestHandlerFallsThroughWithNoAcceptEncodingHeader(t *testing.T) {
+	h := newEncodingHandler(t)
+	rec := getWithAcceptEncoding(t, h, "")
+
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q, want unset", got)
+	}
+	if rec.Body.String() != "plain" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "plain")
+	}
+}
+
+func TestHandlerSetsVaryHeader(t *testing.T) {
+	h := newEncodingHandler(t)
+	rec := getWithAcceptEncoding(t, h, "gzip")
+
+	if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Fatalf("Vary = %q, want %q", got, "Accept-Encoding")
+	}
+}
+
+func TestHandlerFallsBackForNonZipBackedFileSystem(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(dir+"/foo.css", []byte("plain"), 0644); err != nil {
+		t.Fatalf("writing foo.css: %v", err)
+	}
+
+	h := Handler(http.Dir(dir))
+	rec := getWithAcceptEncoding(t, h, "gzip, br")
+
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q, want unset for a non-zip filesystem", got)
+	}
+	if rec.Body.String() != "plain" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "plain")
+	}
+}
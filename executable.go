@@ -0,0 +1,188 @@
+package zipfs
+
+import (
+	"archive/zip"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// NewZipFSFromExecutable opens the zip archive appended to the executable at path. This
+// is the pattern used to ship a single self-contained binary: build the executable,
+// then `cat asset.zip >> application`.
+//
+// archive/zip only finds the archive when its end-of-central-directory record sits at
+// the very end of the file, which breaks as soon as a linker, packer or code-signer adds
+// trailing bytes after it (common on macOS and Windows). To cope with that,
+// NewZipFSFromExecutable tries, in order:
+//
+//  1. The fast path: open the whole file as a zip, which works when nothing was
+//     appended after it.
+//  2. Parse the file as ELF, PE or Mach-O and scan each section for an embedded zip,
+//     skipping sections that have no on-disk content (e.g. SHT_NOBITS/.bss).
+//  3. A SectionReader starting just past the end of the last section found by whichever
+//     of the above formats was recognised.
+//
+// It returns the http.FileSystem for the first archive found along with the byte offset
+// within the file at which the readerAt backing that filesystem starts, so callers
+// needing the raw readerAt (for NewZipFSWithReaderAt-style reuse) don't have to
+// re-derive it. The offset is 0 when the fast path succeeds, including for a zip
+// appended after the executable, since archive/zip then locates the archive itself.
+func NewZipFSFromExecutable(path string) (http.FileSystem, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	ok := false
+	defer func() {
+		if !ok {
+			f.Close()
+		}
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	size := info.Size()
+
+	if z, err := zip.NewReader(f, size); err == nil {
+		ok = true
+		return NewZipFSWithReaderAt(z, f), 0, nil
+	}
+
+	var maxSectionEnd int64
+	for _, scan := range []func(io.ReaderAt, int64) (*zip.Reader, int64, int64, error){
+		scanELFForZip,
+		scanPEForZip,
+		scanMachOForZip,
+	} {
+		z, offset, sectionEnd, err := scan(f, size)
+		if sectionEnd > maxSectionEnd {
+			maxSectionEnd = sectionEnd
+		}
+		if err != nil {
+			continue
+		}
+		ok = true
+		return NewZipFSWithReaderAt(z, io.NewSectionReader(f, offset, size-offset)), offset, nil
+	}
+
+	if maxSectionEnd > 0 && maxSectionEnd < size {
+		sr := io.NewSectionReader(f, maxSectionEnd, size-maxSectionEnd)
+		if z, err := zip.NewReader(sr, size-maxSectionEnd); err == nil {
+			ok = true
+			return NewZipFSWithReaderAt(z, sr), maxSectionEnd, nil
+		}
+	}
+
+	return nil, 0, fmt.Errorf("zipfs: no zip archive found in %s", path)
+}
+
+// InitZipFs opens path as a zip file. If path does not exist, or is not a valid zip, it
+// falls back to looking for a zip appended to (or embedded as a section of) the running
+// executable via NewZipFSFromExecutable. It panics if neither source yields a usable
+// archive, since callers generally use it during startup where there is no sensible way
+// to continue without the assets it serves.
+func InitZipFs(path string) http.FileSystem {
+	if f, err := os.Open(path); err == nil {
+		info, statErr := f.Stat()
+		if statErr == nil {
+			if z, err := zip.NewReader(f, info.Size()); err == nil {
+				return NewZipFSWithReaderAt(z, f)
+			}
+		}
+		f.Close()
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		panic(fmt.Errorf("zipfs: %q not found and could not locate running executable: %w", path, err))
+	}
+	fs, _, err := NewZipFSFromExecutable(exe)
+	if err != nil {
+		panic(fmt.Errorf("zipfs: %q not found and no zip embedded in %q: %w", path, exe, err))
+	}
+	return fs
+}
+
+// scanELFForZip looks for an embedded zip in the sections of an ELF binary. It returns
+// the highest section end seen even on failure, so the caller can fall back to reading
+// past it.
+func scanELFForZip(r io.ReaderAt, size int64) (*zip.Reader, int64, int64, error) {
+	ef, err := elf.NewFile(r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer ef.Close()
+
+	var maxEnd int64
+	for _, sect := range ef.Sections {
+		if sect.Type == elf.SHT_NOBITS {
+			continue
+		}
+		offset, end := int64(sect.Offset), int64(sect.Offset+sect.Size)
+		if end > maxEnd {
+			maxEnd = end
+		}
+		sr := io.NewSectionReader(r, offset, int64(sect.Size))
+		if z, err := zip.NewReader(sr, int64(sect.Size)); err == nil {
+			return z, offset, maxEnd, nil
+		}
+	}
+	return nil, 0, maxEnd, fmt.Errorf("zipfs: no zip section found in ELF binary")
+}
+
+// scanPEForZip looks for an embedded zip in the sections of a PE binary.
+func scanPEForZip(r io.ReaderAt, size int64) (*zip.Reader, int64, int64, error) {
+	pf, err := pe.NewFile(r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer pf.Close()
+
+	var maxEnd int64
+	for _, sect := range pf.Sections {
+		if sect.Size == 0 {
+			continue
+		}
+		offset, end := int64(sect.Offset), int64(sect.Offset+sect.Size)
+		if end > maxEnd {
+			maxEnd = end
+		}
+		sr := io.NewSectionReader(r, offset, int64(sect.Size))
+		if z, err := zip.NewReader(sr, int64(sect.Size)); err == nil {
+			return z, offset, maxEnd, nil
+		}
+	}
+	return nil, 0, maxEnd, fmt.Errorf("zipfs: no zip section found in PE binary")
+}
+
+// scanMachOForZip looks for an embedded zip in the sections of a Mach-O binary.
+func scanMachOForZip(r io.ReaderAt, size int64) (*zip.Reader, int64, int64, error) {
+	mf, err := macho.NewFile(r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer mf.Close()
+
+	var maxEnd int64
+	for _, sect := range mf.Sections {
+		if sect.Size == 0 {
+			continue
+		}
+		offset, end := int64(sect.Offset), int64(sect.Offset)+int64(sect.Size)
+		if end > maxEnd {
+			maxEnd = end
+		}
+		sr := io.NewSectionReader(r, offset, int64(sect.Size))
+		if z, err := zip.NewReader(sr, int64(sect.Size)); err == nil {
+			return z, offset, maxEnd, nil
+		}
+	}
+	return nil, 0, maxEnd, fmt.Errorf("zipfs: no zip section found in Mach-O binary")
+}
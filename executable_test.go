@@ -0,0 +1,195 @@
+package zipfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"debug/elf"
+	"debug/pe"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildZipBytes returns a zip archive (as raw bytes) containing a single file.
+func buildZipBytes(t *testing.T, name, content string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	writeFile(t, w, name, 0644, content)
+	if err := w.Close(); err != nil {
+		t.Fatalf("closing test archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// trailingPadding is appended at the very end of the synthetic ELF/PE images below,
+// standing in for the bytes a linker, packer or code-signer adds after everything else
+// in a real packed executable. It's sized past archive/zip's 65KiB end-of-central-
+// directory search window, so "parse the whole file as a zip" -- NewZipFSFromExecutable's
+// fast path -- can no longer locate the EOCD record and genuinely fails, forcing the
+// section-scan fallback under test to find the zip where it's declared: in its own
+// section, after an earlier, unrelated one.
+var trailingPadding = bytes.Repeat([]byte{0}, 70*1024)
+
+// buildELFWithSectionZip returns a minimal ELF64 relocatable object -- a header, an
+// unrelated leading PROGBITS section, a PROGBITS section holding the zip archive
+// verbatim, and a shstrtab -- with trailingPadding appended after everything so the
+// whole-file fast path can't find the zip and the section scan (scanELFForZip) has to.
+func buildELFWithSectionZip(t *testing.T, zipData []byte) []byte {
+	t.Helper()
+
+	lead := []byte("not-a-zip-section")
+	shstrtab := []byte("\x00.data\x00.zip\x00.shstrtab\x00")
+
+	const ehsize = 64
+	leadOff := int64(ehsize)
+	zipOff := leadOff + int64(len(lead))
+	shstrOff := zipOff + int64(len(zipData))
+	shOff := shstrOff + int64(len(shstrtab))
+
+	hdr := elf.Header64{
+		Ident:     [elf.EI_NIDENT]byte{0x7f, 'E', 'L', 'F', byte(elf.ELFCLASS64), byte(elf.ELFDATA2LSB), byte(elf.EV_CURRENT)},
+		Type:      uint16(elf.ET_REL),
+		Machine:   uint16(elf.EM_X86_64),
+		Version:   uint32(elf.EV_CURRENT),
+		Shoff:     uint64(shOff),
+		Ehsize:    ehsize,
+		Shentsize: 64,
+		Shnum:     4,
+		Shstrndx:  3,
+	}
+
+	buf := &bytes.Buffer{}
+	if err := binary.Write(buf, binary.LittleEndian, hdr); err != nil {
+		t.Fatalf("writing ELF header: %v", err)
+	}
+	buf.Write(lead)
+	buf.Write(zipData)
+	buf.Write(shstrtab)
+
+	sections := []elf.Section64{
+		{}, // SHT_NULL
+		{Name: 1, Type: uint32(elf.SHT_PROGBITS), Off: uint64(leadOff), Size: uint64(len(lead))},
+		{Name: 7, Type: uint32(elf.SHT_PROGBITS), Off: uint64(zipOff), Size: uint64(len(zipData))},
+		{Name: 12, Type: uint32(elf.SHT_STRTAB), Off: uint64(shstrOff), Size: uint64(len(shstrtab))},
+	}
+	for _, sh := range sections {
+		if err := binary.Write(buf, binary.LittleEndian, sh); err != nil {
+			t.Fatalf("writing ELF section header: %v", err)
+		}
+	}
+
+	buf.Write(trailingPadding)
+	return buf.Bytes()
+}
+
+// buildPEWithSectionZip returns a minimal bare COFF object (no MZ/DOS stub, no optional
+// header, no symbol table) with an unrelated leading section followed by a second
+// section holding the zip archive verbatim, with trailingPadding appended after
+// everything so the whole-file fast path can't find the zip and the section scan
+// (scanPEForZip) has to.
+func buildPEWithSectionZip(t *testing.T, zipData []byte) []byte {
+	t.Helper()
+
+	lead := []byte("not-a-zip-section")
+
+	const fileHeaderSize = 20
+	const sectionHeaderSize = 40
+	leadOff := uint32(fileHeaderSize + 2*sectionHeaderSize)
+	zipOff := leadOff + uint32(len(lead))
+
+	fh := pe.FileHeader{
+		Machine:              0, // IMAGE_FILE_MACHINE_UNKNOWN
+		NumberOfSections:     2,
+		SizeOfOptionalHeader: 0,
+	}
+
+	buf := &bytes.Buffer{}
+	if err := binary.Write(buf, binary.LittleEndian, fh); err != nil {
+		t.Fatalf("writing PE file header: %v", err)
+	}
+
+	var leadName, zipName [8]byte
+	copy(leadName[:], ".data")
+	copy(zipName[:], ".zip")
+	sections := []pe.SectionHeader32{
+		{Name: leadName, SizeOfRawData: uint32(len(lead)), PointerToRawData: leadOff},
+		{Name: zipName, SizeOfRawData: uint32(len(zipData)), PointerToRawData: zipOff},
+	}
+	for _, sh := range sections {
+		if err := binary.Write(buf, binary.LittleEndian, sh); err != nil {
+			t.Fatalf("writing PE section header: %v", err)
+		}
+	}
+
+	buf.Write(lead)
+	buf.Write(zipData)
+	buf.Write(trailingPadding)
+	return buf.Bytes()
+}
+
+func TestNewZipFSFromExecutableFindsELFZipPastSectionBoundary(t *testing.T) {
+	zipData := buildZipBytes(t, "asset.txt", "hello-from-elf")
+	image := buildELFWithSectionZip(t, zipData)
+
+	path := filepath.Join(t.TempDir(), "app.elf")
+	if err := os.WriteFile(path, image, 0755); err != nil {
+		t.Fatalf("writing synthetic ELF: %v", err)
+	}
+
+	fs, offset, err := NewZipFSFromExecutable(path)
+	if err != nil {
+		t.Fatalf("NewZipFSFromExecutable: %v", err)
+	}
+	if offset <= 0 {
+		t.Fatalf("offset = %d, want a positive offset past the ELF sections", offset)
+	}
+
+	f, err := fs.Open("/asset.txt")
+	if err != nil {
+		t.Fatalf("Open(/asset.txt): %v", err)
+	}
+	defer f.Close()
+
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("reading /asset.txt: %v", err)
+	}
+	if string(got) != "hello-from-elf" {
+		t.Fatalf("content = %q, want %q", got, "hello-from-elf")
+	}
+}
+
+func TestNewZipFSFromExecutableFindsPEZipPastSectionBoundary(t *testing.T) {
+	zipData := buildZipBytes(t, "asset.txt", "hello-from-pe")
+	image := buildPEWithSectionZip(t, zipData)
+
+	path := filepath.Join(t.TempDir(), "app.exe")
+	if err := os.WriteFile(path, image, 0755); err != nil {
+		t.Fatalf("writing synthetic PE: %v", err)
+	}
+
+	fs, offset, err := NewZipFSFromExecutable(path)
+	if err != nil {
+		t.Fatalf("NewZipFSFromExecutable: %v", err)
+	}
+	if offset <= 0 {
+		t.Fatalf("offset = %d, want a positive offset past the PE section", offset)
+	}
+
+	f, err := fs.Open("/asset.txt")
+	if err != nil {
+		t.Fatalf("Open(/asset.txt): %v", err)
+	}
+	defer f.Close()
+
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("reading /asset.txt: %v", err)
+	}
+	if string(got) != "hello-from-pe" {
+		t.Fatalf("content = %q, want %q", got, "hello-from-pe")
+	}
+}
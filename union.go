@@ -0,0 +1,98 @@
+package zipfs
+
+import (
+	"io"
+	"net/http"
+	"os"
+)
+
+// NewUnionFS merges several http.FileSystem layers into one, with earlier layers taking
+// precedence over later ones. A typical use is serving a base asset zip with individual
+// files overridden from disk or from a small patch zip, without having to rebuild the
+// base archive:
+//
+//	fs := zipfs.NewUnionFS(http.Dir("./overrides"), zipfs.InitZipFs("asset.zip"))
+//
+// Open returns the first layer's file for a given name. Readdir on a directory merges
+// the entries of every layer that has a directory of that name, de-duplicating by file
+// name and preferring the FileInfo from the highest-priority layer that has an entry
+// with that name.
+func NewUnionFS(layers ...http.FileSystem) http.FileSystem {
+	return unionFS(layers)
+}
+
+type unionFS []http.FileSystem
+
+func (u unionFS) Open(name string) (http.File, error) {
+	var firstErr error
+	for _, layer := range u {
+		f, err := layer.Open(name)
+		if err == nil {
+			info, statErr := f.Stat()
+			if statErr == nil && info.IsDir() {
+				return u.openDir(name, f)
+			}
+			return f, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		firstErr = os.ErrNotExist
+	}
+	return nil, firstErr
+}
+
+// openDir wraps the directory already opened from the highest-priority layer so that its
+// Readdir reflects the union of every layer's entries for name, rather than just that
+// one layer's.
+func (u unionFS) openDir(name string, top http.File) (http.File, error) {
+	seen := map[string]os.FileInfo{}
+	order := []string{}
+
+	for _, layer := range u {
+		f, err := layer.Open(name)
+		if err != nil {
+			continue
+		}
+		infos, err := f.Readdir(-1)
+		f.Close()
+		if err != nil {
+			continue
+		}
+		for _, info := range infos {
+			if _, ok := seen[info.Name()]; ok {
+				continue
+			}
+			seen[info.Name()] = info
+			order = append(order, info.Name())
+		}
+	}
+
+	infos := make([]os.FileInfo, len(order))
+	for i, name := range order {
+		infos[i] = seen[name]
+	}
+
+	return &unionDir{File: top, infos: infos}, nil
+}
+
+// unionDir overrides Readdir on an http.File with a precomputed, merged listing while
+// leaving Read/Seek/Stat/Close to the wrapped file.
+type unionDir struct {
+	http.File
+	infos []os.FileInfo
+}
+
+func (d *unionDir) Readdir(count int) ([]os.FileInfo, error) {
+	if len(d.infos) == 0 {
+		return nil, io.EOF
+	}
+	if count <= 0 || count > len(d.infos) {
+		count = len(d.infos)
+	}
+	infos := d.infos[:count]
+	d.infos = d.infos[count:]
+	return infos, nil
+}
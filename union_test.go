@@ -0,0 +1,102 @@
+package zipfs
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newDirLayer creates a directory under t.TempDir populated with files, and returns it
+// as an http.FileSystem layer.
+func newDirLayer(t *testing.T, files map[string]string) http.FileSystem {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		full := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("mkdir for %s: %v", name, err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	return http.Dir(dir)
+}
+
+func TestUnionFSOpenPrefersHigherPriorityLayer(t *testing.T) {
+	top := newDirLayer(t, map[string]string{"shared.txt": "top"})
+	bottom := newDirLayer(t, map[string]string{"shared.txt": "bottom", "only-bottom.txt": "bottom-only"})
+
+	fs := NewUnionFS(top, bottom)
+
+	f, err := fs.Open("/shared.txt")
+	if err != nil {
+		t.Fatalf("Open(/shared.txt): %v", err)
+	}
+	got, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("reading /shared.txt: %v", err)
+	}
+	if string(got) != "top" {
+		t.Fatalf("content = %q, want %q (top layer should win)", got, "top")
+	}
+
+	f, err = fs.Open("/only-bottom.txt")
+	if err != nil {
+		t.Fatalf("Open(/only-bottom.txt): %v", err)
+	}
+	got, err = io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("reading /only-bottom.txt: %v", err)
+	}
+	if string(got) != "bottom-only" {
+		t.Fatalf("content = %q, want %q (fallthrough to lower layer)", got, "bottom-only")
+	}
+}
+
+func TestUnionFSReaddirDedupsAndPrefersHigherPriorityLayer(t *testing.T) {
+	top := newDirLayer(t, map[string]string{"shared.txt": "top", "only-top.txt": "top-only"})
+	bottom := newDirLayer(t, map[string]string{"shared.txt": "bottom", "only-bottom.txt": "bottom-only"})
+
+	fs := NewUnionFS(top, bottom)
+
+	f, err := fs.Open("/")
+	if err != nil {
+		t.Fatalf("Open(/): %v", err)
+	}
+	defer f.Close()
+
+	infos, err := f.Readdir(-1)
+	if err != nil {
+		t.Fatalf("Readdir: %v", err)
+	}
+
+	byName := map[string]os.FileInfo{}
+	for _, info := range infos {
+		if _, dup := byName[info.Name()]; dup {
+			t.Fatalf("Readdir returned %q more than once", info.Name())
+		}
+		byName[info.Name()] = info
+	}
+
+	for _, name := range []string{"shared.txt", "only-top.txt", "only-bottom.txt"} {
+		if _, ok := byName[name]; !ok {
+			t.Fatalf("Readdir missing %q, got %v", name, byName)
+		}
+	}
+
+	wantSizes := map[string]int64{
+		"shared.txt":      int64(len("top")),
+		"only-top.txt":    int64(len("top-only")),
+		"only-bottom.txt": int64(len("bottom-only")),
+	}
+	for name, want := range wantSizes {
+		if got := byName[name].Size(); got != want {
+			t.Fatalf("%s size = %d, want %d (top layer's FileInfo should win for shared entries)", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package zipfs
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ErrResourceChanged is returned when the remote resource's validator (ETag, falling
+// back to Last-Modified) changes between the initial HEAD probe and a later Range
+// request.  It means the bytes already read may no longer correspond to the current
+// content, and the caller should discard the filesystem and retry.
+var ErrResourceChanged = errors.New("zipfs: remote resource changed while reading")
+
+// NewZipFSFromURL opens a zip archive hosted at url and serves it without downloading
+// the whole file up front.  It probes the resource with a HEAD request to discover its
+// size and pins a validator (ETag, falling back to Last-Modified) to detect the file
+// changing mid-flight, then reads the central directory and individual entries via HTTP
+// Range requests as they are needed.  This lets an archive hosted on S3 or behind a CDN
+// be served directly, without fetching it to local disk first.
+func NewZipFSFromURL(url string) (http.FileSystem, error) {
+	return NewZipFSFromURLWithClient(url, http.DefaultClient)
+}
+
+// NewZipFSFromURLWithClient is like NewZipFSFromURL but lets the caller supply the
+// *http.Client used for the HEAD and Range requests, e.g. to set a timeout, a proxy or
+// an authenticating transport.
+func NewZipFSFromURLWithClient(url string, client *http.Client) (http.FileSystem, error) {
+	ra, err := newHTTPReaderAt(url, client)
+	if err != nil {
+		return nil, err
+	}
+	z, err := zip.NewReader(ra, ra.size)
+	if err != nil {
+		return nil, err
+	}
+	return NewZipFSWithReaderAt(z, ra), nil
+}
+
+// httpReaderAtBlockSize is the granularity at which bytes are fetched and cached.  The
+// central directory and local file headers are small and clustered, so a modest block
+// size keeps the number of Range requests low without pulling down whole entries.
+const httpReaderAtBlockSize = 64 * 1024
+
+// httpReaderAtCacheBlocks bounds the number of blocks kept in memory, mainly to amortize
+// the repeated reads archive/zip does over the central directory.
+const httpReaderAtCacheBlocks = 32
+
+// httpReaderAt is an io.ReaderAt backed by HTTP Range requests against a single URL. It
+// is deliberately simple: no prefetching, no concurrent range merging, just a small LRU
+// of fixed-size blocks.
+type httpReaderAt struct {
+	url       string
+	client    *http.Client
+	size      int64
+	validator string
+
+	cache *blockLRU
+}
+
+func newHTTPReaderAt(url string, client *http.Client) (*httpReaderAt, error) {
+	resp, err := client.Head(url)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("zipfs: HEAD %s: unexpected status %s", url, resp.Status)
+	}
+	if resp.ContentLength < 0 {
+		return nil, fmt.Errorf("zipfs: HEAD %s: server did not report a Content-Length", url)
+	}
+	if resp.Header.Get("Accept-Ranges") != "bytes" {
+		return nil, fmt.Errorf("zipfs: %s does not advertise Range support", url)
+	}
+
+	validator := resp.Header.Get("ETag")
+	if validator == "" {
+		validator = resp.Header.Get("Last-Modified")
+	}
+
+	return &httpReaderAt{
+		url:       url,
+		client:    client,
+		size:      resp.ContentLength,
+		validator: validator,
+		cache:     newBlockLRU(httpReaderAtCacheBlocks),
+	}, nil
+}
+
+func (r *httpReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 || off >= r.size {
+		return 0, io.EOF
+	}
+
+	n := 0
+	for n < len(p) {
+		block := (off + int64(n)) / httpReaderAtBlockSize
+		data, err := r.readBlock(block)
+		if err != nil {
+			return n, err
+		}
+		start := int((off + int64(n)) % httpReaderAtBlockSize)
+		if start >= len(data) {
+			break
+		}
+		n += copy(p[n:], data[start:])
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (r *httpReaderAt) readBlock(block int64) ([]byte, error) {
+	if data, ok := r.cache.get(block); ok {
+		return data, nil
+	}
+
+	start := block * httpReaderAtBlockSize
+	if start >= r.size {
+		return nil, io.EOF
+	}
+	end := start + httpReaderAtBlockSize - 1
+	if end > r.size-1 {
+		end = r.size - 1
+	}
+
+	req, err := http.NewRequest(http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return nil, fmt.Errorf("zipfs: GET %s: unexpected status %s", r.url, resp.Status)
+	}
+	if !r.validatorMatches(resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")) {
+		return nil, ErrResourceChanged
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.put(block, data)
+	return data, nil
+}
+
+func (r *httpReaderAt) validatorMatches(etag, lastModified string) bool {
+	if r.validator == "" {
+		return true
+	}
+	if etag != "" {
+		return etag == r.validator
+	}
+	if lastModified != "" {
+		return lastModified == r.validator
+	}
+	return true
+}
+
+// blockLRU is a fixed-capacity, least-recently-used cache of byte blocks keyed by block
+// index. It exists purely to cut down on repeated Range requests for the same bytes,
+// chiefly the central directory, which archive/zip re-reads piecemeal.
+//
+// The io.ReaderAt contract allows parallel ReadAt calls on the same source -- which is exactly what happens when http.FileServer serves several requests
+// concurrently -- so every method here takes mu before touching the map or the order
+// slice.
+type blockLRU struct {
+	mu    sync.Mutex
+	cap   int
+	order []int64
+	data  map[int64][]byte
+}
+
+func newBlockLRU(cap int) *blockLRU {
+	return &blockLRU{cap: cap, data: make(map[int64][]byte, cap)}
+}
+
+func (c *blockLRU) get(key int64) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.data[key]
+	if ok {
+		c.touch(key)
+	}
+	return data, ok
+}
+
+func (c *blockLRU) put(key int64, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.data[key]; !ok && len(c.order) >= c.cap {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		delete(c.data, oldest)
+	}
+	c.data[key] = data
+	c.touch(key)
+}
+
+func (c *blockLRU) touch(key int64) {
+	for i, k := range c.order {
+		if k == key {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	c.order = append(c.order, key)
+}
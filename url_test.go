@@ -0,0 +1,172 @@
+package zipfs
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// rangeServer serves content from a fixed in-memory resource, honoring Range requests
+// the way httpReaderAt expects (Accept-Ranges: bytes, 206 + Content-Range on GET). The
+// validator it reports (etag, if set, else a fixed Last-Modified) can be swapped mid-test
+// via the etag field to simulate the resource changing underneath a reader.
+type rangeServer struct {
+	mu      sync.Mutex
+	content []byte
+	etag    string
+}
+
+func newRangeServer(content []byte, etag string) *httptest.Server {
+	rs := &rangeServer{content: content, etag: etag}
+	return httptest.NewServer(rs)
+}
+
+func (rs *rangeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rs.mu.Lock()
+	content, etag := rs.content, rs.etag
+	rs.mu.Unlock()
+
+	w.Header().Set("Accept-Ranges", "bytes")
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	} else {
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+	}
+
+	if r.Method == http.MethodHead {
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	rng := r.Header.Get("Range")
+	if rng == "" {
+		w.WriteHeader(http.StatusOK)
+		w.Write(content)
+		return
+	}
+
+	var start, end int
+	if _, err := fmt.Sscanf(rng, "bytes=%d-%d", &start, &end); err != nil {
+		http.Error(w, "bad range", http.StatusBadRequest)
+		return
+	}
+	if start > end || start < 0 || end >= len(content) {
+		http.Error(w, "range not satisfiable", http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+
+	w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(content)))
+	w.WriteHeader(http.StatusPartialContent)
+	w.Write(content[start : end+1])
+}
+
+func (rs *rangeServer) setETag(etag string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.etag = etag
+}
+
+func TestHTTPReaderAtReadsViaRange(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 1000)) // 10000 bytes
+	srv := newRangeServer(content, `"v1"`)
+	defer srv.Close()
+
+	ra, err := newHTTPReaderAt(srv.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("newHTTPReaderAt: %v", err)
+	}
+	if ra.size != int64(len(content)) {
+		t.Fatalf("size = %d, want %d", ra.size, len(content))
+	}
+
+	got := make([]byte, 500)
+	n, err := ra.ReadAt(got, 250)
+	if err != nil {
+		t.Fatalf("ReadAt: %v", err)
+	}
+	if n != len(got) || string(got) != string(content[250:750]) {
+		t.Fatalf("ReadAt(250, 500) = %q, want %q", got, content[250:750])
+	}
+}
+
+func TestHTTPReaderAtDetectsResourceChange(t *testing.T) {
+	content := []byte(strings.Repeat("x", 5000))
+	rs := &rangeServer{content: content, etag: `"v1"`}
+	srv := httptest.NewServer(rs)
+	defer srv.Close()
+
+	ra, err := newHTTPReaderAt(srv.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("newHTTPReaderAt: %v", err)
+	}
+
+	rs.setETag(`"v2"`)
+
+	buf := make([]byte, 10)
+	_, err = ra.ReadAt(buf, 0)
+	if err != ErrResourceChanged {
+		t.Fatalf("ReadAt after ETag change: err = %v, want ErrResourceChanged", err)
+	}
+}
+
+func TestHTTPReaderAtReadAtPastEOFOnBlockAlignedResource(t *testing.T) {
+	content := make([]byte, httpReaderAtBlockSize) // exact multiple of the block size
+	srv := newRangeServer(content, `"v1"`)
+	defer srv.Close()
+
+	ra, err := newHTTPReaderAt(srv.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("newHTTPReaderAt: %v", err)
+	}
+
+	buf := make([]byte, 2)
+	n, err := ra.ReadAt(buf, ra.size-1)
+	if err != io.EOF {
+		t.Fatalf("ReadAt at size-1 on block-aligned resource: err = %v, want io.EOF", err)
+	}
+	if n != 1 {
+		t.Fatalf("ReadAt at size-1: n = %d, want 1", n)
+	}
+}
+
+func TestHTTPReaderAtConcurrentReadAt(t *testing.T) {
+	content := []byte(strings.Repeat("abcdefghij", 2000)) // 20000 bytes
+	srv := newRangeServer(content, `"v1"`)
+	defer srv.Close()
+
+	ra, err := newHTTPReaderAt(srv.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("newHTTPReaderAt: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+	for i := 0; i < 32; i++ {
+		off := int64(i % len(content))
+		wg.Add(1)
+		go func(off int64) {
+			defer wg.Done()
+			buf := make([]byte, 100)
+			n, err := ra.ReadAt(buf, off)
+			if err != nil && err != io.EOF {
+				errs <- err
+				return
+			}
+			want := content[off : off+int64(n)]
+			if string(buf[:n]) != string(want) {
+				errs <- fmt.Errorf("ReadAt(%d) = %q, want %q", off, buf[:n], want)
+			}
+		}(off)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
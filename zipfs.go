@@ -36,6 +36,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path"
 	"strings"
 	"time"
 )
@@ -97,7 +98,19 @@ type zipFS struct {
 	trie     *trie
 }
 
+// maxSymlinkHops bounds how many symlinks Open will follow before giving up, to guard
+// against cycles (e.g. a -> b -> a).
+const maxSymlinkHops = 8
+
+// maxSymlinkTargetSize caps how much of a symlink entry's body Open will read as the
+// link target, so a maliciously large "symlink" can't be used to exhaust memory.
+const maxSymlinkTargetSize = 256
+
 func (fs *zipFS) Open(name string) (http.File, error) {
+	return fs.open(name, 0)
+}
+
+func (fs *zipFS) open(name string, hops int) (http.File, error) {
 	if !strings.HasPrefix(name, "/") {
 		return nil, os.ErrNotExist
 	}
@@ -108,6 +121,9 @@ func (fs *zipFS) Open(name string) (http.File, error) {
 
 	switch entry := node.meta.(type) {
 	case *zip.File:
+		if entry.Mode()&os.ModeSymlink != 0 {
+			return fs.openSymlink(name, entry, hops)
+		}
 		return fs.processZipFile(entry)
 	case zipDir:
 		return &entry, nil
@@ -118,6 +134,40 @@ func (fs *zipFS) Open(name string) (http.File, error) {
 	return nil, os.ErrNotExist
 }
 
+// openSymlink resolves a symlink entry against the directory it lives in and re-dispatches
+// Open on the result. It refuses to follow more than maxSymlinkHops links (to break
+// cycles) and refuses targets that, once cleaned, escape the archive root.
+func (fs *zipFS) openSymlink(name string, entry *zip.File, hops int) (http.File, error) {
+	if hops >= maxSymlinkHops {
+		return nil, errors.New("zipfs: too many levels of symbolic links: " + name)
+	}
+
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	target, err := io.ReadAll(io.LimitReader(rc, maxSymlinkTargetSize+1))
+	rc.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(target) > maxSymlinkTargetSize {
+		return nil, errors.New("zipfs: symlink target too long: " + name)
+	}
+
+	// Targets are always resolved relative to the link's own directory within the
+	// archive, regardless of whether they look absolute. The join is done on an unrooted
+	// path so that ".." segments climbing above the archive root survive cleaning and
+	// can be refused.
+	rel := path.Join(".", strings.TrimPrefix(path.Dir(name), "/"), string(target))
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return nil, errors.New("zipfs: symlink target escapes archive root: " + name)
+	}
+	resolved := path.Join("/", rel)
+
+	return fs.open(resolved, hops+1)
+}
+
 func (fs *zipFS) processZipFile(entry *zip.File) (http.File, error) {
 	if fs.readerAt != nil && entry.Method == zip.Store {
 		offset, err := entry.DataOffset()
@@ -129,6 +179,13 @@ func (fs *zipFS) processZipFile(entry *zip.File) (http.File, error) {
 			zipFile:       entry,
 		}, nil
 	}
+	if fs.readerAt != nil && entry.Method == zip.Deflate {
+		offset, err := entry.DataOffset()
+		if err != nil {
+			return nil, err
+		}
+		return newSeekableDeflateFile(entry, fs.readerAt, offset)
+	}
 	ff, err := entry.Open()
 	if err != nil {
 		return nil, err
@@ -0,0 +1,127 @@
+package zipfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+)
+
+// buildSymlinkZip returns a zip archive (as raw bytes) containing:
+//
+//	real.txt       -- a regular file
+//	link.txt       -- a symlink to real.txt
+//	sub/link.txt   -- a symlink to ../real.txt
+//	dangling.txt   -- a symlink to does-not-exist.txt
+//	loop-a.txt     -- a symlink to loop-b.txt
+//	loop-b.txt     -- a symlink to loop-a.txt
+func buildSymlinkZip(t *testing.T) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+
+	writeFile(t, w, "real.txt", 0644, "hello")
+	writeSymlink(t, w, "link.txt", "real.txt")
+	writeSymlink(t, w, "sub/link.txt", "../real.txt")
+	writeSymlink(t, w, "dangling.txt", "does-not-exist.txt")
+	writeSymlink(t, w, "loop-a.txt", "loop-b.txt")
+	writeSymlink(t, w, "loop-b.txt", "loop-a.txt")
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("closing test archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func writeFile(t *testing.T, w *zip.Writer, name string, mode os.FileMode, content string) {
+	t.Helper()
+	hdr := &zip.FileHeader{Name: name, Method: zip.Store}
+	hdr.SetMode(mode)
+	f, err := w.CreateHeader(hdr)
+	if err != nil {
+		t.Fatalf("creating %s: %v", name, err)
+	}
+	if _, err := f.Write([]byte(content)); err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+}
+
+func writeSymlink(t *testing.T, w *zip.Writer, name, target string) {
+	t.Helper()
+	hdr := &zip.FileHeader{Name: name, Method: zip.Store}
+	hdr.SetMode(os.ModeSymlink | 0777)
+	f, err := w.CreateHeader(hdr)
+	if err != nil {
+		t.Fatalf("creating symlink %s: %v", name, err)
+	}
+	if _, err := f.Write([]byte(target)); err != nil {
+		t.Fatalf("writing symlink target for %s: %v", name, err)
+	}
+}
+
+func openSymlinkFS(t *testing.T) http.FileSystem {
+	t.Helper()
+	data := buildSymlinkZip(t)
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("reading test archive: %v", err)
+	}
+	return NewZipFS(z)
+}
+
+func TestOpenFollowsIntraArchiveSymlink(t *testing.T) {
+	fs := openSymlinkFS(t)
+
+	f, err := fs.Open("/link.txt")
+	if err != nil {
+		t.Fatalf("Open(/link.txt): %v", err)
+	}
+	defer f.Close()
+
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("reading through symlink: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Fatalf("content through symlink = %q, want %q", got, "hello")
+	}
+}
+
+func TestOpenFollowsRelativeSymlinkFromSubdirectory(t *testing.T) {
+	fs := openSymlinkFS(t)
+
+	f, err := fs.Open("/sub/link.txt")
+	if err != nil {
+		t.Fatalf("Open(/sub/link.txt): %v", err)
+	}
+	defer f.Close()
+
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("reading through symlink: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Fatalf("content through symlink = %q, want %q", got, "hello")
+	}
+}
+
+func TestOpenDanglingSymlinkReturnsNotExist(t *testing.T) {
+	fs := openSymlinkFS(t)
+
+	_, err := fs.Open("/dangling.txt")
+	if !os.IsNotExist(err) {
+		t.Fatalf("Open(/dangling.txt) error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestOpenSymlinkLoopIsBounded(t *testing.T) {
+	fs := openSymlinkFS(t)
+
+	_, err := fs.Open("/loop-a.txt")
+	if err == nil {
+		t.Fatalf("Open(/loop-a.txt) succeeded, want an error from the symlink loop")
+	}
+}